@@ -0,0 +1,18 @@
+package lilliput
+
+func isAvif(buf []byte) bool {
+	return len(buf) >= 12 && string(buf[4:8]) == "ftyp" &&
+		(string(buf[8:12]) == "avif" || string(buf[8:12]) == "avis")
+}
+
+// newAvifDecoder always fails: this build has no AVIF codec. isAvif is
+// still used by NewDecoder so AVIF sources are identified and rejected
+// with ErrFormatNotSupported rather than the less specific ErrInvalidImage.
+func newAvifDecoder(buf []byte) (Decoder, error) {
+	return nil, ErrFormatNotSupported
+}
+
+// newAvifEncoder always fails; see newAvifDecoder.
+func newAvifEncoder(decodedBy Decoder, dst []byte, policy MetadataPolicy) (Encoder, error) {
+	return nil, ErrFormatNotSupported
+}
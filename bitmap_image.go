@@ -0,0 +1,118 @@
+package lilliput
+
+import (
+	"image"
+	"time"
+)
+
+// Pixel types understood by Framebuffer. These mirror the subset of
+// pixel layouts lilliput actually round-trips.
+const (
+	PixelTypeGrey = iota
+	PixelTypeGreyAlpha
+	PixelTypeRGB
+	PixelTypeRGBA
+)
+
+// ImageHeader carries metadata parsed from an image's header without
+// requiring the full image to be decoded.
+type ImageHeader struct {
+	width        int
+	height       int
+	pixelType    int
+	orientation  int
+	numFrames    int
+	hasSubtitles bool
+}
+
+// Width returns the image width in pixels.
+func (h *ImageHeader) Width() int { return h.width }
+
+// Height returns the image height in pixels.
+func (h *ImageHeader) Height() int { return h.height }
+
+// PixelType returns one of the PixelType* constants describing the
+// decoded pixel layout.
+func (h *ImageHeader) PixelType() int { return h.pixelType }
+
+// OrientationDegrees returns the clockwise rotation (0, 90, 180, or 270)
+// needed to display the image upright, derived from the EXIF
+// orientation tag. Mirrored orientations (EXIF values 2, 4, 5, 7) are
+// reduced to their nearest rotation; the mirror itself is not applied.
+func (h *ImageHeader) OrientationDegrees() int { return h.orientation }
+
+// IsAnimated reports whether the source contains more than one frame.
+func (h *ImageHeader) IsAnimated() bool { return h.numFrames > 1 }
+
+// HasSubtitles reports whether the source carries a subtitle track, as
+// can happen with animated WEBP/AVIF sources transcoded from video.
+func (h *ImageHeader) HasSubtitles() bool { return h.hasSubtitles }
+
+// Framebuffer holds a single decoded frame of pixel data along with the
+// metadata needed to resize and re-encode it. Framebuffers are reused
+// across frames by ImageOps to avoid reallocating for animated sources.
+type Framebuffer struct {
+	img      image.Image
+	duration time.Duration
+}
+
+// NewFramebuffer returns an empty Framebuffer. maxPixels is accepted for
+// API compatibility with callers sizing a scratch buffer up front, but
+// standard library image codecs allocate their own backing storage per
+// frame, so it isn't used to pre-size anything here.
+func NewFramebuffer(maxPixels int) *Framebuffer {
+	return &Framebuffer{}
+}
+
+// Width returns the width of the currently held frame.
+func (f *Framebuffer) Width() int {
+	if f.img == nil {
+		return 0
+	}
+	return f.img.Bounds().Dx()
+}
+
+// Height returns the height of the currently held frame.
+func (f *Framebuffer) Height() int {
+	if f.img == nil {
+		return 0
+	}
+	return f.img.Bounds().Dy()
+}
+
+// PixelType returns the pixel layout of the currently held frame.
+func (f *Framebuffer) PixelType() int { return PixelTypeRGBA }
+
+// Clear resets the Framebuffer so it can be reused for the next frame.
+func (f *Framebuffer) Clear() {
+	f.img = nil
+	f.duration = 0
+}
+
+// resizeTo scales the frame to the given dimensions in place, ignoring
+// its original aspect ratio.
+func (f *Framebuffer) resizeTo(width, height int) error {
+	if f.img == nil || width <= 0 || height <= 0 {
+		return nil
+	}
+	f.img = resizeNearest(f.img, width, height)
+	return nil
+}
+
+// fitInto scales the frame to cover width x height while preserving
+// aspect ratio, then center-crops to exactly width x height.
+func (f *Framebuffer) fitInto(width, height int) error {
+	if f.img == nil || width <= 0 || height <= 0 {
+		return nil
+	}
+	f.img = resizeToFill(f.img, width, height)
+	return nil
+}
+
+// rotate applies a clockwise rotation of 0, 90, 180, or 270 degrees.
+func (f *Framebuffer) rotate(degrees int) {
+	if f.img == nil {
+		return
+	}
+	f.img = rotateImage(f.img, degrees)
+}
@@ -0,0 +1,18 @@
+// Package cache provides a content-addressed cache for lilliput
+// transforms, so repeat requests for the same source bytes and
+// ImageOptions can skip decode/resize/encode entirely.
+package cache
+
+import "time"
+
+// TransformCache stores encoded transform output keyed by a caller
+// supplied key (see Key). Implementations must be safe for concurrent
+// use.
+type TransformCache interface {
+	// Get returns the cached value for key, if present and unexpired.
+	Get(key string) ([]byte, bool)
+	// Put stores data under key, to be evicted no later than ttl from
+	// now. A ttl of zero means the entry never expires on its own
+	// (though an LRU cache may still evict it under memory pressure).
+	Put(key string, data []byte, ttl time.Duration)
+}
@@ -0,0 +1,111 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// FilesystemCache stores entries as individual files under a directory,
+// named after their key. It is safe to share that directory across
+// processes on the same host.
+type FilesystemCache struct {
+	dir string
+}
+
+// NewFilesystemCache returns a FilesystemCache rooted at dir, creating it
+// if necessary.
+func NewFilesystemCache(dir string) (*FilesystemCache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &FilesystemCache{dir: dir}, nil
+}
+
+// Get implements TransformCache. Entries past their TTL are treated as
+// absent and removed on next access.
+func (c *FilesystemCache) Get(key string) ([]byte, bool) {
+	path := c.path(key)
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+
+	expiresAt, payload, ok := splitExpiry(data)
+	if !ok {
+		return nil, false
+	}
+	if !expiresAt.IsZero() && time.Now().After(expiresAt) {
+		os.Remove(path)
+		return nil, false
+	}
+	return payload, true
+}
+
+// Put implements TransformCache. Entries are written via a temp file
+// plus rename so a concurrent Get never observes a partially written
+// file.
+func (c *FilesystemCache) Put(key string, data []byte, ttl time.Duration) {
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = time.Now().Add(ttl)
+	}
+
+	tmp, err := ioutil.TempFile(c.dir, "tmp-*")
+	if err != nil {
+		return
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(joinExpiry(expiresAt, data)); err != nil {
+		tmp.Close()
+		return
+	}
+	if err := tmp.Close(); err != nil {
+		return
+	}
+	os.Rename(tmp.Name(), c.path(key))
+}
+
+// path maps key to a filename under c.dir. Keys come from Key(), which
+// embeds caller-supplied option strings (e.g. a requested output
+// format) verbatim; hashing rather than joining key directly keeps a
+// key containing path separators or ".." segments from escaping dir.
+func (c *FilesystemCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:]))
+}
+
+// expiryHeaderLen is the fixed width of the zero-padded Unix timestamp
+// ("0" meaning "no expiry") prepended to every stored entry, so
+// FilesystemCache doesn't need a second file per key just to track TTLs.
+const expiryHeaderLen = 20
+
+func joinExpiry(expiresAt time.Time, data []byte) []byte {
+	var unix int64
+	if !expiresAt.IsZero() {
+		unix = expiresAt.Unix()
+	}
+	header := []byte(fmt.Sprintf("%0*d", expiryHeaderLen, unix))
+	return append(header, data...)
+}
+
+func splitExpiry(payload []byte) (time.Time, []byte, bool) {
+	if len(payload) < expiryHeaderLen {
+		return time.Time{}, nil, false
+	}
+	unix, err := strconv.ParseInt(string(payload[:expiryHeaderLen]), 10, 64)
+	if err != nil {
+		return time.Time{}, nil, false
+	}
+	var expiresAt time.Time
+	if unix != 0 {
+		expiresAt = time.Unix(unix, 0)
+	}
+	return expiresAt, payload[expiryHeaderLen:], true
+}
@@ -0,0 +1,41 @@
+package cache
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFilesystemCacheGetPut(t *testing.T) {
+	c, err := NewFilesystemCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFilesystemCache: %v", err)
+	}
+	c.Put("a", []byte("hello"), 0)
+
+	got, ok := c.Get("a")
+	if !ok || string(got) != "hello" {
+		t.Fatalf("got %q, %v; want \"hello\", true", got, ok)
+	}
+
+	if _, ok := c.Get("missing"); ok {
+		t.Fatalf("expected miss for unknown key")
+	}
+}
+
+func TestFilesystemCachePathDoesNotEscapeDir(t *testing.T) {
+	dir := t.TempDir()
+	c, err := NewFilesystemCache(dir)
+	if err != nil {
+		t.Fatalf("NewFilesystemCache: %v", err)
+	}
+
+	maliciousKey := "h:t=../../../../etc/passwd,w=0,h=0,m=0,o=false,p=0,q="
+	path := c.path(maliciousKey)
+
+	if !strings.HasPrefix(path, dir) {
+		t.Fatalf("path %q escaped cache dir %q", path, dir)
+	}
+	if strings.Contains(path, "..") {
+		t.Fatalf("path %q still contains a traversal segment", path)
+	}
+}
@@ -0,0 +1,56 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	"github.com/discordapp/lilliput"
+)
+
+// Key returns a stable cache key for src transformed with opts: the
+// SHA-256 of src, followed by a canonical serialization of the
+// ImageOptions fields that affect the output (dimensions, resize
+// method, orientation normalization, metadata policy, format, and
+// encode options).
+func Key(src []byte, opts *lilliput.ImageOptions) string {
+	sum := sha256.Sum256(src)
+	return hex.EncodeToString(sum[:]) + ":" + canonicalizeOptions(opts)
+}
+
+func canonicalizeOptions(opts *lilliput.ImageOptions) string {
+	return fmt.Sprintf(
+		"t=%s,w=%d,h=%d,m=%d,o=%t,p=%d,q=%s",
+		opts.FileType,
+		opts.Width,
+		opts.Height,
+		opts.ResizeMethod,
+		opts.NormalizeOrientation,
+		opts.MetadataPolicy,
+		canonicalizeEncodeOptions(opts.EncodeOptions),
+	)
+}
+
+// canonicalizeEncodeOptions serializes opts in ascending key order so
+// that two equal maps always produce the same string, regardless of Go's
+// randomized map iteration order.
+func canonicalizeEncodeOptions(opts map[int]int) string {
+	if len(opts) == 0 {
+		return ""
+	}
+	keys := make([]int, 0, len(opts))
+	for k := range opts {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+
+	out := ""
+	for i, k := range keys {
+		if i > 0 {
+			out += ","
+		}
+		out += fmt.Sprintf("%d=%d", k, opts[k])
+	}
+	return out
+}
@@ -0,0 +1,27 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/discordapp/lilliput"
+)
+
+func TestKeyDiffersOnNormalizeOrientation(t *testing.T) {
+	src := []byte("source bytes")
+	base := &lilliput.ImageOptions{FileType: ".jpeg", Width: 100, Height: 100}
+	rotated := &lilliput.ImageOptions{FileType: ".jpeg", Width: 100, Height: 100, NormalizeOrientation: true}
+
+	if Key(src, base) == Key(src, rotated) {
+		t.Fatalf("keys should differ when NormalizeOrientation differs")
+	}
+}
+
+func TestKeyStableForEquivalentOptions(t *testing.T) {
+	src := []byte("source bytes")
+	a := &lilliput.ImageOptions{FileType: ".jpeg", Width: 100, Height: 100, EncodeOptions: map[int]int{lilliput.JpegQuality: 85, lilliput.PngCompression: 7}}
+	b := &lilliput.ImageOptions{FileType: ".jpeg", Width: 100, Height: 100, EncodeOptions: map[int]int{lilliput.PngCompression: 7, lilliput.JpegQuality: 85}}
+
+	if Key(src, a) != Key(src, b) {
+		t.Fatalf("keys should be stable regardless of map iteration order")
+	}
+}
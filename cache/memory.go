@@ -0,0 +1,81 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// MemoryCache is an in-process, size-bounded LRU TransformCache.
+type MemoryCache struct {
+	mu       sync.Mutex
+	maxBytes int64
+	curBytes int64
+	ll       *list.List // front = most recently used
+	items    map[string]*list.Element
+}
+
+type memoryEntry struct {
+	key       string
+	data      []byte
+	expiresAt time.Time // zero means no expiry
+}
+
+// NewMemoryCache returns a MemoryCache that evicts least-recently-used
+// entries once the total size of cached values exceeds maxBytes.
+func NewMemoryCache(maxBytes int64) *MemoryCache {
+	return &MemoryCache{
+		maxBytes: maxBytes,
+		ll:       list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+// Get implements TransformCache.
+func (c *MemoryCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	entry := el.Value.(*memoryEntry)
+	if !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt) {
+		c.removeElement(el)
+		return nil, false
+	}
+	c.ll.MoveToFront(el)
+	return entry.data, true
+}
+
+// Put implements TransformCache.
+func (c *MemoryCache) Put(key string, data []byte, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		c.removeElement(el)
+	}
+
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = time.Now().Add(ttl)
+	}
+
+	entry := &memoryEntry{key: key, data: data, expiresAt: expiresAt}
+	el := c.ll.PushFront(entry)
+	c.items[key] = el
+	c.curBytes += int64(len(data))
+
+	for c.maxBytes > 0 && c.curBytes > c.maxBytes && c.ll.Len() > 1 {
+		c.removeElement(c.ll.Back())
+	}
+}
+
+func (c *MemoryCache) removeElement(el *list.Element) {
+	entry := el.Value.(*memoryEntry)
+	c.ll.Remove(el)
+	delete(c.items, entry.key)
+	c.curBytes -= int64(len(entry.data))
+}
@@ -0,0 +1,56 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMemoryCacheGetPut(t *testing.T) {
+	c := NewMemoryCache(1024)
+	c.Put("a", []byte("hello"), 0)
+
+	got, ok := c.Get("a")
+	if !ok || string(got) != "hello" {
+		t.Fatalf("got %q, %v; want \"hello\", true", got, ok)
+	}
+
+	if _, ok := c.Get("missing"); ok {
+		t.Fatalf("expected miss for unknown key")
+	}
+}
+
+func TestMemoryCacheLRUEviction(t *testing.T) {
+	// each entry is 4 bytes; a budget of 10 bytes fits 2 entries at most.
+	c := NewMemoryCache(10)
+	c.Put("a", []byte("aaaa"), 0)
+	c.Put("b", []byte("bbbb"), 0)
+
+	// touch "a" so it's more recently used than "b"
+	if _, ok := c.Get("a"); !ok {
+		t.Fatalf("expected a to still be present")
+	}
+
+	// this should evict "b", the least recently used entry
+	c.Put("c", []byte("cccc"), 0)
+
+	if _, ok := c.Get("b"); ok {
+		t.Fatalf("expected b to be evicted as least recently used")
+	}
+	if _, ok := c.Get("a"); !ok {
+		t.Fatalf("expected a to survive eviction")
+	}
+	if _, ok := c.Get("c"); !ok {
+		t.Fatalf("expected c to be present")
+	}
+}
+
+func TestMemoryCacheTTLExpiry(t *testing.T) {
+	c := NewMemoryCache(1024)
+	c.Put("a", []byte("hello"), time.Nanosecond)
+
+	time.Sleep(time.Millisecond)
+
+	if _, ok := c.Get("a"); ok {
+		t.Fatalf("expected entry to be expired")
+	}
+}
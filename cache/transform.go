@@ -0,0 +1,93 @@
+package cache
+
+import (
+	"sync"
+	"time"
+
+	"github.com/discordapp/lilliput"
+)
+
+// DefaultTTL is used by Group.TransformCached when callers don't need
+// per-call control over expiry.
+const DefaultTTL = 24 * time.Hour
+
+// call tracks an in-flight transform so concurrent callers for the same
+// key can wait on and share its result instead of each running their own
+// Transform.
+type call struct {
+	wg     sync.WaitGroup
+	result []byte
+	err    error
+}
+
+// Group coalesces concurrent TransformCached calls that share a cache
+// key, so that N simultaneous requests for the same source image and
+// ImageOptions trigger exactly one ImageOps.Transform. It mirrors the
+// shape of golang.org/x/sync/singleflight.Group. The zero value is ready
+// to use.
+type Group struct {
+	mu    sync.Mutex
+	calls map[string]*call
+}
+
+// Do runs fn and returns its result, coalescing concurrent calls that
+// share key so only one of them actually invokes fn.
+func (g *Group) Do(key string, fn func() ([]byte, error)) ([]byte, error) {
+	g.mu.Lock()
+	if c, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		c.wg.Wait()
+		return c.result, c.err
+	}
+
+	c := new(call)
+	c.wg.Add(1)
+	if g.calls == nil {
+		g.calls = make(map[string]*call)
+	}
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	c.result, c.err = fn()
+	c.wg.Done()
+
+	g.mu.Lock()
+	delete(g.calls, key)
+	g.mu.Unlock()
+
+	return c.result, c.err
+}
+
+// TransformCached behaves like ops.Transform(decoder, opts, dst), except
+// that it first checks c for a cached result keyed on src and opts, and
+// on a miss stores the freshly transformed output under ttl (DefaultTTL
+// if zero) before returning it. decoder must have been constructed from
+// src (e.g. via lilliput.NewDecoder(src)) so the cache key matches what's
+// actually being transformed. Concurrent calls for the same key are
+// coalesced via g so only one of them runs Transform.
+func (g *Group) TransformCached(ops *lilliput.ImageOps, c TransformCache, decoder lilliput.Decoder, src []byte, opts *lilliput.ImageOptions, dst []byte, ttl time.Duration) ([]byte, error) {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	key := Key(src, opts)
+
+	if cached, ok := c.Get(key); ok {
+		return cached, nil
+	}
+
+	return g.Do(key, func() ([]byte, error) {
+		if cached, ok := c.Get(key); ok {
+			return cached, nil
+		}
+		out, err := ops.Transform(decoder, opts, dst)
+		if err != nil {
+			return nil, err
+		}
+		// copy out of dst before caching: dst is caller-owned and may be
+		// reused for the next Transform call.
+		cached := make([]byte, len(out))
+		copy(cached, out)
+		c.Put(key, cached, ttl)
+		return cached, nil
+	})
+}
@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestGroupDoCoalescesConcurrentCalls(t *testing.T) {
+	var g Group
+	var calls int32
+	start := make(chan struct{})
+
+	// entered is released only once all 10 goroutines are about to make
+	// their g.Do call, so fn (run by whichever goroutine wins the race)
+	// blocks on it first thing. That leaves the other 9 only the g.Do
+	// call itself to make while fn is still running, exercising the
+	// coalescing path instead of letting each caller complete its own Do
+	// before the next starts. It narrows that window but does not close
+	// it: a goroutine descheduled between entered.Done and g.Do can still
+	// reach Do after fn has returned.
+	var entered sync.WaitGroup
+	entered.Add(10)
+
+	var wg sync.WaitGroup
+	results := make([][]byte, 10)
+	for i := 0; i < 10; i++ {
+		i := i
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			entered.Done()
+			out, err := g.Do("shared-key", func() ([]byte, error) {
+				entered.Wait()
+				atomic.AddInt32(&calls, 1)
+				return []byte("result"), nil
+			})
+			if err != nil {
+				t.Errorf("Do: %v", err)
+			}
+			results[i] = out
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	if calls != 1 {
+		t.Fatalf("got %d calls, want exactly 1 (all callers should coalesce)", calls)
+	}
+	for i, r := range results {
+		if string(r) != "result" {
+			t.Errorf("result[%d] = %q, want \"result\"", i, r)
+		}
+	}
+}
+
+func TestGroupDoSeparateKeysRunIndependently(t *testing.T) {
+	var g Group
+	var calls int32
+
+	g.Do("a", func() ([]byte, error) { atomic.AddInt32(&calls, 1); return nil, nil })
+	g.Do("b", func() ([]byte, error) { atomic.AddInt32(&calls, 1); return nil, nil })
+
+	if calls != 2 {
+		t.Fatalf("got %d calls, want 2 for distinct keys", calls)
+	}
+}
@@ -0,0 +1,59 @@
+// Command lilliput-server runs lilliput's HTTP resize endpoint.
+//
+// It doubles as its own resize worker: when invoked as
+// "lilliput-server --resize-worker" it reads a transform request from
+// stdin and writes the encoded image to stdout instead of starting the
+// HTTP server. The server re-execs itself this way for every transform,
+// so a crash while decoding or encoding an image only takes down the
+// worker subprocess.
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/discordapp/lilliput"
+	"github.com/discordapp/lilliput/cache"
+	"github.com/discordapp/lilliput/server"
+)
+
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == server.WorkerFlag {
+		os.Exit(server.RunWorker(os.Stdin, os.Stdout))
+	}
+
+	addr := flag.String("addr", ":8080", "address to listen on")
+	maxConcurrency := flag.Int("max-concurrency", 8, "maximum number of concurrent transforms")
+	requestTimeout := flag.Duration("request-timeout", 10*time.Second, "deadline for a single /resize request")
+	cacheBytes := flag.Int64("cache-bytes", 256*1024*1024, "max bytes of resize results to keep in the in-memory cache; 0 disables caching")
+	maxSourcePixels := flag.Int64("max-source-pixels", 40000000, "reject upstream images decoding to more than this many pixels; 0 disables the check")
+	maxSourceBytes := flag.Int64("max-source-bytes", 50*1024*1024, "reject upstream images whose encoded bytes exceed this size; 0 disables the check")
+	flag.Parse()
+
+	var transformCache cache.TransformCache
+	if *cacheBytes > 0 {
+		transformCache = cache.NewMemoryCache(*cacheBytes)
+	}
+
+	s := server.New(server.Config{
+		MaxConcurrency: *maxConcurrency,
+		RequestTimeout: *requestTimeout,
+		Cache:          transformCache,
+		Preflight: lilliput.PreflightOptions{
+			MaxSourcePixels: *maxSourcePixels,
+			MaxSourceBytes:  *maxSourceBytes,
+		},
+	})
+
+	mux := http.NewServeMux()
+	mux.Handle("/resize", s)
+	mux.Handle("/metrics", promhttp.Handler())
+
+	log.Printf("lilliput-server listening on %s", *addr)
+	log.Fatal(http.ListenAndServe(*addr, mux))
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"flag"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"os"
@@ -25,12 +26,23 @@ var outputWidth int
 var outputHeight int
 var outputFilename string
 var stretch bool
+var skipUpscale bool
 var runs int
 var remoteInput string
 var wg sync.WaitGroup
 
 var client = &http.Client{}
 
+// remoteInputPreflight is applied to everything fetched via -remoteInput,
+// since that's the path that takes untrusted, unsized input. Local
+// -input files are assumed trusted and skip this check.
+var remoteInputPreflight = lilliput.PreflightOptions{
+	MaxSourceWidth:  10000,
+	MaxSourceHeight: 10000,
+	MaxSourcePixels: 40000000, // 40 megapixels
+	MaxSourceBytes:  50 * 1024 * 1024,
+}
+
 func main() {
 	flag.StringVar(&inputFilename, "input", "", "name of input file to resize/transcode")
 	flag.StringVar(&remoteInput, "remoteInput", "", "URL of input file to resize/transcode")
@@ -39,6 +51,7 @@ func main() {
 	flag.IntVar(&outputHeight, "height", 0, "height of output file")
 	flag.IntVar(&runs, "runs", 1, "number of workers should be spawned for the purpose of testing")
 	flag.BoolVar(&stretch, "stretch", false, "perform stretching resize instead of cropping")
+	flag.BoolVar(&skipUpscale, "skipUpscale", false, "pass through images already smaller than width/height instead of upscaling them")
 	flag.Parse()
 
 	for i := 0; i < runs; i++ {
@@ -68,8 +81,8 @@ func resize(i int) {
 			return
 		}
 	} else if remoteInput != "" {
-		// Fetch remote input URL
-		// decoder wants []byte, so read the whole file into a buffer
+		// bound how much of the remote body is ever materialized, rather
+		// than trusting Content-Length and reading until the server stops
 		inputBuf, err = readRemoteURL(remoteInput)
 		if err != nil {
 			fmt.Printf("failed to read remote input, %s\n", err)
@@ -94,6 +107,13 @@ func resize(i int) {
 		return
 	}
 
+	if remoteInput != "" {
+		if err := lilliput.CheckPreflight(header, len(inputBuf), remoteInputPreflight); err != nil {
+			fmt.Printf("rejecting remote input, %s\n", err)
+			return
+		}
+	}
+
 	// print some basic info about the image
 	fmt.Printf("file type: %s\n", decoder.Description())
 	fmt.Printf("%dpx x %dpx\n", header.Width(), header.Height())
@@ -128,6 +148,8 @@ func resize(i int) {
 	resizeMethod := lilliput.ImageOpsFit
 	if stretch {
 		resizeMethod = lilliput.ImageOpsResize
+	} else if skipUpscale {
+		resizeMethod = lilliput.ImageOpsSkipUpscale
 	}
 
 	opts := &lilliput.ImageOptions{
@@ -165,6 +187,9 @@ func resize(i int) {
 	fmt.Printf("image written to %s\n", outputFilename)
 }
 
+// readRemoteURL fetches url, capping how much of the body is ever
+// materialized at remoteInputPreflight.MaxSourceBytes+1 so a server that
+// ignores Content-Length (or lies about it) can't exhaust memory here.
 func readRemoteURL(url string) ([]byte, error) {
 	resp, err := client.Get(url)
 	if resp != nil {
@@ -174,10 +199,14 @@ func readRemoteURL(url string) ([]byte, error) {
 		return nil, err
 	}
 
-	body, err := ioutil.ReadAll(resp.Body)
+	limit := io.LimitReader(resp.Body, remoteInputPreflight.MaxSourceBytes+1)
+	body, err := ioutil.ReadAll(limit)
 	if err != nil {
 		return nil, err
 	}
+	if int64(len(body)) > remoteInputPreflight.MaxSourceBytes {
+		return nil, lilliput.ErrSourceBytesTooLarge
+	}
 
 	return body, nil
 }
@@ -0,0 +1,136 @@
+package lilliput
+
+import (
+	"bytes"
+	"image"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"io"
+	"time"
+)
+
+type gifDecoder struct {
+	buf    []byte
+	header *ImageHeader
+	all    *gif.GIF
+	frame  int
+}
+
+func newGifDecoder(buf []byte) (Decoder, error) {
+	if _, err := gif.DecodeConfig(bytes.NewReader(buf)); err != nil {
+		return nil, ErrInvalidImage
+	}
+	return &gifDecoder{buf: buf}, nil
+}
+
+func (d *gifDecoder) decodeAll() (*gif.GIF, error) {
+	if d.all == nil {
+		all, err := gif.DecodeAll(bytes.NewReader(d.buf))
+		if err != nil {
+			return nil, err
+		}
+		d.all = all
+	}
+	return d.all, nil
+}
+
+func (d *gifDecoder) Header() (*ImageHeader, error) {
+	if d.header != nil {
+		return d.header, nil
+	}
+	all, err := d.decodeAll()
+	if err != nil {
+		return nil, err
+	}
+	d.header = &ImageHeader{
+		width:     all.Config.Width,
+		height:    all.Config.Height,
+		pixelType: PixelTypeRGBA,
+		numFrames: len(all.Image),
+	}
+	return d.header, nil
+}
+
+func (d *gifDecoder) Description() string { return "GIF" }
+
+// Metadata always returns an empty Metadata: GIF's comment/application
+// extension blocks don't carry EXIF/XMP/ICC data in practice.
+func (d *gifDecoder) Metadata() *Metadata { return &Metadata{} }
+
+func (d *gifDecoder) Duration() time.Duration {
+	all, err := d.decodeAll()
+	if err != nil {
+		return 0
+	}
+	var total time.Duration
+	for _, delay := range all.Delay {
+		total += time.Duration(delay) * 10 * time.Millisecond
+	}
+	return total
+}
+
+func (d *gifDecoder) DecodeTo(f *Framebuffer) error {
+	all, err := d.decodeAll()
+	if err != nil {
+		return err
+	}
+	if d.frame >= len(all.Image) {
+		return io.EOF
+	}
+	f.img = all.Image[d.frame]
+	if d.frame < len(all.Delay) {
+		f.duration = time.Duration(all.Delay[d.frame]) * 10 * time.Millisecond
+	}
+	d.frame++
+	return nil
+}
+
+func (d *gifDecoder) Close() {}
+
+type gifEncoder struct {
+	numLoops int
+	frames   []*image.Paletted
+	delays   []int
+}
+
+func newGifEncoder(decodedBy Decoder, dst []byte, policy MetadataPolicy) (Encoder, error) {
+	return &gifEncoder{}, nil
+}
+
+func (e *gifEncoder) Encode(f *Framebuffer, opts map[int]int) ([]byte, error) {
+	if n, ok := opts[GifNumLoops]; ok {
+		e.numLoops = n
+	}
+	delayHundredths := int(f.duration / (10 * time.Millisecond))
+	e.frames = append(e.frames, imageToPaletted(f.img))
+	e.delays = append(e.delays, delayHundredths)
+	return e.flush()
+}
+
+func (e *gifEncoder) flush() ([]byte, error) {
+	var buf bytes.Buffer
+	err := gif.EncodeAll(&buf, &gif.GIF{
+		Image:     e.frames,
+		Delay:     e.delays,
+		LoopCount: e.numLoops,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (e *gifEncoder) Close() {}
+
+// imageToPaletted converts src to a paletted image suitable for GIF
+// encoding, using the Plan 9 palette when src isn't already paletted.
+func imageToPaletted(src image.Image) *image.Paletted {
+	if p, ok := src.(*image.Paletted); ok {
+		return p
+	}
+	b := src.Bounds()
+	dst := image.NewPaletted(b, palette.Plan9)
+	draw.FloydSteinberg.Draw(dst, b, src, b.Min)
+	return dst
+}
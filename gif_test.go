@@ -0,0 +1,70 @@
+package lilliput
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"testing"
+)
+
+func encodeTestGIF(t *testing.T, frames int) []byte {
+	t.Helper()
+	g := &gif.GIF{}
+	for i := 0; i < frames; i++ {
+		img := image.NewPaletted(image.Rect(0, 0, 3, 3), []color.Color{color.Black, color.White})
+		g.Image = append(g.Image, img)
+		g.Delay = append(g.Delay, 10)
+	}
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, g); err != nil {
+		t.Fatalf("gif.EncodeAll: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestGifDecoderAnimatedHeader(t *testing.T) {
+	buf := encodeTestGIF(t, 3)
+	dec, err := newGifDecoder(buf)
+	if err != nil {
+		t.Fatalf("newGifDecoder: %v", err)
+	}
+	defer dec.Close()
+
+	header, err := dec.Header()
+	if err != nil {
+		t.Fatalf("Header: %v", err)
+	}
+	if !header.IsAnimated() {
+		t.Fatalf("expected IsAnimated() for a 3-frame GIF")
+	}
+	if header.Width() != 3 || header.Height() != 3 {
+		t.Fatalf("got %dx%d, want 3x3", header.Width(), header.Height())
+	}
+}
+
+func TestGifDecodeToAllFrames(t *testing.T) {
+	buf := encodeTestGIF(t, 2)
+	dec, err := newGifDecoder(buf)
+	if err != nil {
+		t.Fatalf("newGifDecoder: %v", err)
+	}
+	defer dec.Close()
+
+	fb := NewFramebuffer(1024)
+	for i := 0; i < 2; i++ {
+		if err := dec.DecodeTo(fb); err != nil {
+			t.Fatalf("DecodeTo frame %d: %v", i, err)
+		}
+	}
+	if err := dec.DecodeTo(fb); err == nil {
+		t.Fatalf("expected io.EOF after all frames consumed")
+	}
+}
+
+func TestImageToPalettedPassesThroughPaletted(t *testing.T) {
+	src := image.NewPaletted(image.Rect(0, 0, 2, 2), []color.Color{color.Black, color.White})
+	if got := imageToPaletted(src); got != src {
+		t.Fatalf("expected already-paletted image to pass through unchanged")
+	}
+}
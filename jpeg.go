@@ -0,0 +1,181 @@
+package lilliput
+
+import (
+	"bytes"
+	"image/jpeg"
+	"io"
+	"time"
+)
+
+type jpegDecoder struct {
+	buf      []byte
+	header   *ImageHeader
+	metadata *Metadata
+	done     bool
+}
+
+func newJpegDecoder(buf []byte) (Decoder, error) {
+	if _, err := jpeg.DecodeConfig(bytes.NewReader(buf)); err != nil {
+		return nil, ErrInvalidImage
+	}
+	return &jpegDecoder{buf: buf}, nil
+}
+
+func (d *jpegDecoder) Header() (*ImageHeader, error) {
+	if d.header != nil {
+		return d.header, nil
+	}
+	cfg, err := jpeg.DecodeConfig(bytes.NewReader(d.buf))
+	if err != nil {
+		return nil, err
+	}
+	d.header = &ImageHeader{
+		width:       cfg.Width,
+		height:      cfg.Height,
+		pixelType:   PixelTypeRGBA,
+		orientation: exifOrientationDegrees(d.Metadata().Exif),
+		numFrames:   1,
+	}
+	return d.header, nil
+}
+
+func (d *jpegDecoder) Description() string {
+	return "JPEG"
+}
+
+func (d *jpegDecoder) Duration() time.Duration {
+	return 0
+}
+
+// Metadata parses the source's APPn segments on first call and caches
+// the result. APP1 carries EXIF ("Exif\x00\x00" prefix) or XMP
+// ("http://ns.adobe.com/xap/1.0/\x00" prefix); APP2 carries the ICC
+// profile by convention.
+func (d *jpegDecoder) Metadata() *Metadata {
+	if d.metadata == nil {
+		d.metadata = parseJPEGMetadata(d.buf)
+	}
+	return d.metadata
+}
+
+func (d *jpegDecoder) DecodeTo(f *Framebuffer) error {
+	if d.done {
+		return io.EOF
+	}
+	img, err := jpeg.Decode(bytes.NewReader(d.buf))
+	if err != nil {
+		return err
+	}
+	f.img = img
+	d.done = true
+	return nil
+}
+
+func (d *jpegDecoder) Close() {}
+
+// jpegExifPrefix/jpegXMPPrefix distinguish the two payloads JPEG
+// overloads onto the APP1 marker.
+var jpegExifPrefix = []byte("Exif\x00\x00")
+var jpegXMPPrefix = []byte("http://ns.adobe.com/xap/1.0/\x00")
+
+// jpegICCPrefix starts each APP2 chunk of an embedded ICC profile; it is
+// followed by a chunk sequence number and chunk count byte.
+var jpegICCPrefix = []byte("ICC_PROFILE\x00")
+
+// parseJPEGSegments walks buf's marker segments, stopping at the first
+// Start Of Scan (0xDA), and returns every APPn segment found along the
+// way.
+func parseJPEGSegments(buf []byte) []jpegAPPSegment {
+	var segs []jpegAPPSegment
+	i := 2 // skip SOI (0xFFD8)
+	for i+4 <= len(buf) {
+		if buf[i] != 0xFF {
+			break
+		}
+		marker := buf[i+1]
+		if marker == 0xD8 || marker == 0xD9 {
+			i += 2
+			continue
+		}
+		if marker == 0xDA {
+			break
+		}
+		length := int(buf[i+2])<<8 | int(buf[i+3])
+		if length < 2 || i+2+length > len(buf) {
+			break
+		}
+		data := buf[i+4 : i+2+length]
+		if marker >= 0xE0 && marker <= 0xEF {
+			segs = append(segs, jpegAPPSegment{marker: marker, data: data})
+		}
+		i += 2 + length
+	}
+	return segs
+}
+
+func parseJPEGMetadata(buf []byte) *Metadata {
+	m := &Metadata{}
+	for _, seg := range parseJPEGSegments(buf) {
+		switch {
+		case seg.marker == 0xE1 && hasPrefix(seg.data, jpegExifPrefix):
+			m.Exif = seg.data[len(jpegExifPrefix):]
+		case seg.marker == 0xE1 && hasPrefix(seg.data, jpegXMPPrefix):
+			m.XMP = seg.data[len(jpegXMPPrefix):]
+		case seg.marker == 0xE2 && hasPrefix(seg.data, jpegICCPrefix) && len(seg.data) >= len(jpegICCPrefix)+2:
+			// chunks are concatenated in the order they appear
+			m.ICCProfile = append(m.ICCProfile, seg.data[len(jpegICCPrefix)+2:]...)
+		}
+	}
+	return m
+}
+
+func hasPrefix(buf, prefix []byte) bool {
+	return len(buf) >= len(prefix) && string(buf[:len(prefix)]) == string(prefix)
+}
+
+type jpegEncoder struct {
+	segs []jpegAPPSegment
+}
+
+func newJpegEncoder(decodedBy Decoder, dst []byte, policy MetadataPolicy) (Encoder, error) {
+	var segs []jpegAPPSegment
+	if src, ok := decodedBy.(*jpegDecoder); ok {
+		segs = filterJPEGSegments(parseJPEGSegments(src.buf), policy)
+	}
+	return &jpegEncoder{segs: segs}, nil
+}
+
+func (e *jpegEncoder) Encode(f *Framebuffer, opts map[int]int) ([]byte, error) {
+	quality := 85
+	if q, ok := opts[JpegQuality]; ok {
+		quality = q
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, f.img, &jpeg.Options{Quality: quality}); err != nil {
+		return nil, err
+	}
+	return spliceJPEGSegments(buf.Bytes(), e.segs), nil
+}
+
+func (e *jpegEncoder) Close() {}
+
+// spliceJPEGSegments inserts segs immediately after encoded's SOI
+// marker. image/jpeg.Encode has no API for writing custom APPn segments,
+// so metadata is applied as a post-processing step on its output rather
+// than threaded through the encoder itself.
+func spliceJPEGSegments(encoded []byte, segs []jpegAPPSegment) []byte {
+	if len(segs) == 0 || len(encoded) < 2 {
+		return encoded
+	}
+
+	out := make([]byte, 0, len(encoded)+16*len(segs))
+	out = append(out, encoded[:2]...) // SOI
+	for _, seg := range segs {
+		length := len(seg.data) + 2
+		out = append(out, 0xFF, seg.marker, byte(length>>8), byte(length))
+		out = append(out, seg.data...)
+	}
+	out = append(out, encoded[2:]...)
+	return out
+}
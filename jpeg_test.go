@@ -0,0 +1,97 @@
+package lilliput
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+)
+
+func encodeTestJPEG(t *testing.T) []byte {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, 4, 4))
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			img.Set(x, y, color.NRGBA{R: uint8(x * 50), G: uint8(y * 50), B: 100, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, nil); err != nil {
+		t.Fatalf("jpeg.Encode: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestJpegDecoderHeader(t *testing.T) {
+	buf := encodeTestJPEG(t)
+	dec, err := newJpegDecoder(buf)
+	if err != nil {
+		t.Fatalf("newJpegDecoder: %v", err)
+	}
+	defer dec.Close()
+
+	header, err := dec.Header()
+	if err != nil {
+		t.Fatalf("Header: %v", err)
+	}
+	if header.Width() != 4 || header.Height() != 4 {
+		t.Fatalf("got %dx%d, want 4x4", header.Width(), header.Height())
+	}
+}
+
+func TestJpegRoundTrip(t *testing.T) {
+	buf := encodeTestJPEG(t)
+	dec, err := newJpegDecoder(buf)
+	if err != nil {
+		t.Fatalf("newJpegDecoder: %v", err)
+	}
+	defer dec.Close()
+
+	fb := NewFramebuffer(1024)
+	if err := dec.DecodeTo(fb); err != nil {
+		t.Fatalf("DecodeTo: %v", err)
+	}
+	if err := dec.DecodeTo(fb); err == nil {
+		t.Fatalf("expected io.EOF decoding a second frame of a still JPEG")
+	}
+
+	enc, err := newJpegEncoder(dec, make([]byte, 0), MetadataPreserve)
+	if err != nil {
+		t.Fatalf("newJpegEncoder: %v", err)
+	}
+	defer enc.Close()
+
+	out, err := enc.Encode(fb, nil)
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	if len(out) == 0 {
+		t.Fatalf("Encode produced no bytes")
+	}
+	if _, err := jpeg.Decode(bytes.NewReader(out)); err != nil {
+		t.Fatalf("re-decoding encoder output: %v", err)
+	}
+}
+
+func TestSpliceJPEGSegments(t *testing.T) {
+	buf := encodeTestJPEG(t)
+	segs := []jpegAPPSegment{{marker: 0xE1, data: []byte("hello")}}
+
+	spliced := spliceJPEGSegments(buf, segs)
+	found := parseJPEGSegments(spliced)
+	if len(found) != 1 || found[0].marker != 0xE1 || string(found[0].data) != "hello" {
+		t.Fatalf("got segments %+v, want one APP1 'hello' segment", found)
+	}
+
+	if _, err := jpeg.Decode(bytes.NewReader(spliced)); err != nil {
+		t.Fatalf("spliced JPEG should still decode: %v", err)
+	}
+}
+
+func TestSpliceJPEGSegmentsNoop(t *testing.T) {
+	buf := encodeTestJPEG(t)
+	if got := spliceJPEGSegments(buf, nil); !bytes.Equal(got, buf) {
+		t.Fatalf("splicing no segments should return input unchanged")
+	}
+}
@@ -0,0 +1,121 @@
+// Package lilliput resizes and re-encodes images. JPEG, PNG and GIF are
+// decoded and encoded using the standard library (image/jpeg, image/png,
+// image/gif). WEBP and AVIF sources are recognized by NewDecoder (so
+// callers can at least identify and reject them cleanly) but cannot be
+// decoded or encoded in this build; see webp.go/avif.go.
+package lilliput
+
+import (
+	"bytes"
+	"errors"
+	"time"
+)
+
+// Decoder decodes a single image, frame by frame. Implementations are not
+// safe for concurrent use and must be Closed to release any resources
+// they hold.
+type Decoder interface {
+	// Header returns metadata parsed from the image header without
+	// decoding pixel data.
+	Header() (*ImageHeader, error)
+
+	// Description returns a short, human readable name for the format,
+	// e.g. "JPEG", "PNG", "WEBP", "GIF", "AVIF".
+	Description() string
+
+	// Duration returns the playback duration of an animated image, or 0
+	// for still images.
+	Duration() time.Duration
+
+	// DecodeTo decodes the next frame into f, resizing f's internal
+	// buffer as necessary. It returns io.EOF once all frames have been
+	// consumed.
+	DecodeTo(f *Framebuffer) error
+
+	// Metadata returns the EXIF/XMP/ICC metadata parsed from the source,
+	// regardless of what MetadataPolicy a later encode uses. Callers
+	// that strip metadata on the way out can still inspect or re-inject
+	// selected fields via the returned Metadata.
+	Metadata() *Metadata
+
+	// Close releases resources associated with the Decoder. It is safe
+	// to call Close multiple times.
+	Close()
+}
+
+// Encoder encodes Framebuffers into a specific output format.
+type Encoder interface {
+	// Encode adds f to the output and returns the complete encoded image
+	// so far (for animated output, every frame encoded up to now).
+	// opts carries format-specific tunables such as JpegQuality.
+	Encode(f *Framebuffer, opts map[int]int) ([]byte, error)
+
+	// Close releases resources associated with the Encoder. It produces
+	// no further output.
+	Close()
+}
+
+// ErrInvalidImage is returned by NewDecoder when the input's magic bytes
+// don't match any supported format.
+var ErrInvalidImage = errors.New("lilliput: unrecognized image format")
+
+// ErrFormatNotSupported is returned by NewDecoder/NewEncoder for formats
+// this build recognizes by magic bytes but cannot actually decode or
+// encode (currently WEBP and AVIF).
+var ErrFormatNotSupported = errors.New("lilliput: format recognized but not supported in this build")
+
+var magicBytesDecoders = []struct {
+	magic   []byte
+	newFunc func([]byte) (Decoder, error)
+}{
+	{[]byte("\xff\xd8\xff"), newJpegDecoder},
+	{[]byte("\x89PNG\r\n\x1a\n"), newPngDecoder},
+	{[]byte("GIF87a"), newGifDecoder},
+	{[]byte("GIF89a"), newGifDecoder},
+}
+
+// NewDecoder returns a Decoder able to handle buf, selected by sniffing
+// its magic bytes. The caller owns buf for the lifetime of the returned
+// Decoder and must not modify it until Close is called.
+func NewDecoder(buf []byte) (Decoder, error) {
+	for _, d := range magicBytesDecoders {
+		if bytes.HasPrefix(buf, d.magic) {
+			return d.newFunc(buf)
+		}
+	}
+	if isWebp(buf) {
+		return newWebpDecoder(buf)
+	}
+	if isAvif(buf) {
+		return newAvifDecoder(buf)
+	}
+	return nil, ErrInvalidImage
+}
+
+// NewEncoder returns an Encoder for the format implied by ext (e.g.
+// ".jpeg", ".png", ".webp", ".gif"). decodedBy is consulted so the
+// encoder can carry over source metadata (animation timing, ICC profile)
+// when it makes sense to, filtered per policy. dst is accepted for API
+// compatibility; the encoders in this build allocate their own output.
+func NewEncoder(ext string, decodedBy Decoder, dst []byte, policy MetadataPolicy) (Encoder, error) {
+	switch normalizeExt(ext) {
+	case ".jpeg":
+		return newJpegEncoder(decodedBy, dst, policy)
+	case ".png":
+		return newPngEncoder(decodedBy, dst, policy)
+	case ".webp":
+		return newWebpEncoder(decodedBy, dst, policy)
+	case ".gif":
+		return newGifEncoder(decodedBy, dst, policy)
+	case ".avif":
+		return newAvifEncoder(decodedBy, dst, policy)
+	}
+	return nil, ErrInvalidImage
+}
+
+func normalizeExt(ext string) string {
+	if len(ext) > 0 && ext[0] != '.' {
+		return "." + ext
+	}
+	return ext
+}
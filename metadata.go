@@ -0,0 +1,158 @@
+package lilliput
+
+// MetadataPolicy controls which ancillary metadata (EXIF, XMP, color
+// profiles) survives into an encoded output. It is applied by the JPEG
+// encoder; the PNG encoder accepts it but has no source metadata to
+// filter (see png.go), and GIF carries no such metadata and ignores it.
+// WEBP and AVIF are recognized by NewDecoder but not encodable in this
+// build (see webp.go/avif.go), so it has no effect there today.
+type MetadataPolicy int
+
+const (
+	// MetadataPreserve copies all metadata chunks/segments through
+	// unchanged. This is the default, matching lilliput's historical
+	// behavior.
+	MetadataPreserve MetadataPolicy = iota
+	// MetadataStripAll removes every ancillary metadata chunk/segment,
+	// including color profiles. Use this when output size matters more
+	// than color fidelity across devices.
+	MetadataStripAll
+	// MetadataStripExceptColorProfile removes all metadata except the
+	// embedded ICC color profile, so colors still render correctly
+	// without carrying EXIF/XMP (camera make/model, GPS, etc.) into
+	// user-generated content.
+	MetadataStripExceptColorProfile
+	// MetadataStripExceptOrientation removes all metadata except the
+	// EXIF block that carries the orientation tag. That block is kept
+	// whole, so its other EXIF fields (camera make/model, GPS, ...) are
+	// kept along with the orientation tag. Pairs with ImageOptions.NormalizeOrientation
+	// = false for callers that want to defer rotation to the client.
+	MetadataStripExceptOrientation
+)
+
+// Metadata holds the parsed ancillary metadata of a decoded image, as
+// returned by Decoder.Metadata().
+type Metadata struct {
+	// Exif holds the raw EXIF TIFF blob (the bytes that would follow a
+	// JPEG APP1 "Exif\x00\x00" header), or nil if the source had none.
+	Exif []byte
+	// XMP holds the raw XMP packet, or nil if the source had none.
+	XMP []byte
+	// ICCProfile holds the raw embedded ICC color profile, or nil if the
+	// source had none.
+	ICCProfile []byte
+}
+
+// jpegAPPSegment keeps the subset of JPEG APPn markers lilliput
+// recognizes when filtering metadata on encode.
+type jpegAPPSegment struct {
+	marker byte // e.g. 0xE1 for APP1, 0xE2 for APP2
+	data   []byte
+}
+
+// filterJPEGSegments returns the subset of segs that should survive
+// encode under policy. APP2 carries the ICC profile by convention; APP1
+// carries EXIF (which is where the orientation tag lives) or XMP; all
+// other APPn segments (APP0 JFIF, ...) are treated as strippable
+// metadata under every policy other than MetadataPreserve.
+func filterJPEGSegments(segs []jpegAPPSegment, policy MetadataPolicy) []jpegAPPSegment {
+	if policy == MetadataPreserve {
+		return segs
+	}
+	out := segs[:0]
+	for _, s := range segs {
+		switch {
+		case policy == MetadataStripExceptColorProfile && s.marker == 0xE2:
+			out = append(out, s)
+		case policy == MetadataStripExceptOrientation && s.marker == 0xE1 && hasPrefix(s.data, jpegExifPrefix):
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// pngColorChunkWhitelist lists the ancillary PNG chunk types kept under
+// MetadataStripExceptColorProfile/MetadataStripExceptOrientation.
+// gAMA/cHRM/iCCP/sRGB affect color interpretation; everything else
+// (tEXt, eXIf, ...) is considered metadata rather than color data.
+var pngColorChunkWhitelist = []string{"gAMA", "cHRM", "iCCP", "sRGB"}
+
+// pngChunksToKeep returns the ancillary PNG chunk types to keep on encode
+// under policy. pngEncoder does not call it (see png.go).
+func pngChunksToKeep(policy MetadataPolicy) []string {
+	switch policy {
+	case MetadataStripAll:
+		return []string{}
+	case MetadataStripExceptColorProfile, MetadataStripExceptOrientation:
+		return pngColorChunkWhitelist
+	default: // MetadataPreserve
+		return nil // nil means "don't filter"
+	}
+}
+
+// exifOrientationTag is the TIFF tag number for the EXIF orientation
+// field within an Exif blob's IFD0.
+const exifOrientationTag = 0x0112
+
+// exifOrientationDegrees parses exif (as returned in Metadata.Exif, i.e.
+// the TIFF blob with the "Exif\x00\x00" prefix already stripped) for the
+// orientation tag and returns the clockwise rotation needed to display
+// the image upright. EXIF orientation values 2, 4, 5, and 7 also imply a
+// mirror; lilliput reduces those to their nearest rotation and does not
+// apply the mirror. Returns 0 if exif is absent, malformed, or carries
+// no orientation tag (including orientation value 1, "normal").
+func exifOrientationDegrees(exif []byte) int {
+	if len(exif) < 8 {
+		return 0
+	}
+
+	var order func([]byte) uint16
+	var order32 func([]byte) uint32
+	switch string(exif[0:2]) {
+	case "II":
+		order = func(b []byte) uint16 { return uint16(b[0]) | uint16(b[1])<<8 }
+		order32 = func(b []byte) uint32 { return uint32(b[0]) | uint32(b[1])<<8 | uint32(b[2])<<16 | uint32(b[3])<<24 }
+	case "MM":
+		order = func(b []byte) uint16 { return uint16(b[1]) | uint16(b[0])<<8 }
+		order32 = func(b []byte) uint32 { return uint32(b[3]) | uint32(b[2])<<8 | uint32(b[1])<<16 | uint32(b[0])<<24 }
+	default:
+		return 0
+	}
+
+	ifdOffset := order32(exif[4:8])
+	if int(ifdOffset)+2 > len(exif) {
+		return 0
+	}
+
+	numEntries := int(order(exif[ifdOffset : ifdOffset+2]))
+	entriesStart := int(ifdOffset) + 2
+	for i := 0; i < numEntries; i++ {
+		entryOffset := entriesStart + i*12
+		if entryOffset+12 > len(exif) {
+			break
+		}
+		tag := order(exif[entryOffset : entryOffset+2])
+		if tag != exifOrientationTag {
+			continue
+		}
+		value := order(exif[entryOffset+8 : entryOffset+10])
+		return orientationTagToDegrees(int(value))
+	}
+	return 0
+}
+
+func orientationTagToDegrees(value int) int {
+	switch value {
+	case 1, 2:
+		return 0
+	case 3, 4:
+		return 180
+	case 6, 7:
+		return 90
+	case 5, 8:
+		return 270
+	default:
+		return 0
+	}
+}
+
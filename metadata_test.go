@@ -0,0 +1,70 @@
+package lilliput
+
+import "testing"
+
+// buildExif constructs a minimal little-endian TIFF/IFD0 blob with a
+// single orientation entry, matching the shape exifOrientationDegrees
+// expects (no "Exif\x00\x00" prefix, that's stripped by the caller).
+func buildExif(orientation uint16) []byte {
+	buf := make([]byte, 8+2+12+4)
+	copy(buf[0:2], "II")
+	buf[2] = 42
+	buf[4] = 8 // IFD0 offset
+
+	numEntries := buf[8:10]
+	numEntries[0] = 1
+
+	entry := buf[10:22]
+	entry[0] = byte(exifOrientationTag & 0xFF)
+	entry[1] = byte(exifOrientationTag >> 8)
+	entry[2] = 3 // type SHORT
+	entry[8] = byte(orientation)
+	entry[9] = byte(orientation >> 8)
+
+	return buf
+}
+
+func TestExifOrientationDegrees(t *testing.T) {
+	cases := map[uint16]int{
+		1: 0,
+		3: 180,
+		6: 90,
+		8: 270,
+	}
+	for orientation, want := range cases {
+		got := exifOrientationDegrees(buildExif(orientation))
+		if got != want {
+			t.Errorf("orientation %d: got %d degrees, want %d", orientation, got, want)
+		}
+	}
+}
+
+func TestExifOrientationDegreesMalformed(t *testing.T) {
+	if got := exifOrientationDegrees(nil); got != 0 {
+		t.Errorf("nil exif: got %d, want 0", got)
+	}
+	if got := exifOrientationDegrees([]byte("short")); got != 0 {
+		t.Errorf("short exif: got %d, want 0", got)
+	}
+}
+
+func TestFilterJPEGSegments(t *testing.T) {
+	segs := []jpegAPPSegment{
+		{marker: 0xE0, data: []byte("JFIF")},
+		{marker: 0xE1, data: append(append([]byte{}, jpegExifPrefix...), buildExif(6)...)},
+		{marker: 0xE2, data: []byte("icc-profile")},
+	}
+
+	if got := filterJPEGSegments(segs, MetadataPreserve); len(got) != 3 {
+		t.Errorf("MetadataPreserve: got %d segments, want 3", len(got))
+	}
+	if got := filterJPEGSegments(segs, MetadataStripAll); len(got) != 0 {
+		t.Errorf("MetadataStripAll: got %d segments, want 0", len(got))
+	}
+	if got := filterJPEGSegments(segs, MetadataStripExceptColorProfile); len(got) != 1 || got[0].marker != 0xE2 {
+		t.Errorf("MetadataStripExceptColorProfile: got %+v, want only APP2", got)
+	}
+	if got := filterJPEGSegments(segs, MetadataStripExceptOrientation); len(got) != 1 || got[0].marker != 0xE1 {
+		t.Errorf("MetadataStripExceptOrientation: got %+v, want only APP1/EXIF", got)
+	}
+}
@@ -0,0 +1,99 @@
+package lilliput
+
+import (
+	"bytes"
+	"image/png"
+	"io"
+	"time"
+)
+
+type pngDecoder struct {
+	buf    []byte
+	header *ImageHeader
+	done   bool
+}
+
+func newPngDecoder(buf []byte) (Decoder, error) {
+	if _, err := png.DecodeConfig(bytes.NewReader(buf)); err != nil {
+		return nil, ErrInvalidImage
+	}
+	return &pngDecoder{buf: buf}, nil
+}
+
+func (d *pngDecoder) Header() (*ImageHeader, error) {
+	if d.header != nil {
+		return d.header, nil
+	}
+	cfg, err := png.DecodeConfig(bytes.NewReader(d.buf))
+	if err != nil {
+		return nil, err
+	}
+	d.header = &ImageHeader{width: cfg.Width, height: cfg.Height, pixelType: PixelTypeRGBA, numFrames: 1}
+	return d.header, nil
+}
+
+func (d *pngDecoder) Description() string { return "PNG" }
+
+func (d *pngDecoder) Duration() time.Duration { return 0 }
+
+// Metadata returns the source's metadata. PNG's EXIF/XMP live in tEXt/
+// eXIf ancillary chunks rather than a single predictable offset, and
+// image/png discards ancillary chunks on decode, so lilliput always
+// returns an empty Metadata for PNG.
+func (d *pngDecoder) Metadata() *Metadata { return &Metadata{} }
+
+func (d *pngDecoder) DecodeTo(f *Framebuffer) error {
+	if d.done {
+		return io.EOF
+	}
+	img, err := png.Decode(bytes.NewReader(d.buf))
+	if err != nil {
+		return err
+	}
+	f.img = img
+	d.done = true
+	return nil
+}
+
+func (d *pngDecoder) Close() {}
+
+type pngEncoder struct {
+	policy MetadataPolicy
+}
+
+// newPngEncoder keeps policy for API symmetry with the other encoders,
+// but since pngDecoder.Metadata never surfaces anything to filter, it
+// has no observable effect here today.
+func newPngEncoder(decodedBy Decoder, dst []byte, policy MetadataPolicy) (Encoder, error) {
+	return &pngEncoder{policy: policy}, nil
+}
+
+func (e *pngEncoder) Encode(f *Framebuffer, opts map[int]int) ([]byte, error) {
+	enc := png.Encoder{CompressionLevel: compressionLevelFor(opts)}
+	var buf bytes.Buffer
+	if err := enc.Encode(&buf, f.img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (e *pngEncoder) Close() {}
+
+// compressionLevelFor maps the PngCompression tunable (0-9, matching
+// libpng's convention) onto image/png's coarser CompressionLevel enum.
+func compressionLevelFor(opts map[int]int) png.CompressionLevel {
+	c, ok := opts[PngCompression]
+	if !ok {
+		return png.DefaultCompression
+	}
+	switch {
+	case c == 0:
+		return png.NoCompression
+	case c <= 3:
+		return png.BestSpeed
+	case c <= 6:
+		return png.DefaultCompression
+	default:
+		return png.BestCompression
+	}
+}
@@ -0,0 +1,49 @@
+package lilliput
+
+import "errors"
+
+// ErrSourceDimensionsTooLarge is returned by CheckPreflight when the
+// source exceeds MaxSourceWidth/MaxSourceHeight. It is named distinctly
+// from stream_decoder.go's ErrSourceTooLarge (which bounds raw byte
+// count while reading, before any header has even been parsed) so the
+// two "too large" checks don't share one ambiguous name.
+var ErrSourceDimensionsTooLarge = errors.New("lilliput: source dimensions exceed preflight limit")
+
+// ErrSourceTooManyPixels is returned by CheckPreflight when
+// width*height exceeds MaxSourcePixels.
+var ErrSourceTooManyPixels = errors.New("lilliput: source pixel count exceeds preflight limit")
+
+// ErrSourceBytesTooLarge is returned by CheckPreflight when the input
+// buffer exceeds MaxSourceBytes.
+var ErrSourceBytesTooLarge = errors.New("lilliput: source byte size exceeds preflight limit")
+
+// PreflightOptions bounds what CheckPreflight will accept, letting
+// callers reject pathological inputs (e.g. a 60000x60000 decompression
+// bomb) before any decode/resize work begins. A zero value for any field
+// means that check is not enforced.
+type PreflightOptions struct {
+	MaxSourceWidth  int
+	MaxSourceHeight int
+	MaxSourcePixels int64
+	MaxSourceBytes  int64
+}
+
+// CheckPreflight validates header and the length of the buffer it was
+// parsed from against opts, returning ErrSourceDimensionsTooLarge,
+// ErrSourceTooManyPixels, or ErrSourceBytesTooLarge on the first check
+// that fails.
+func CheckPreflight(header *ImageHeader, srcLen int, opts PreflightOptions) error {
+	if opts.MaxSourceBytes > 0 && int64(srcLen) > opts.MaxSourceBytes {
+		return ErrSourceBytesTooLarge
+	}
+	if opts.MaxSourceWidth > 0 && header.Width() > opts.MaxSourceWidth {
+		return ErrSourceDimensionsTooLarge
+	}
+	if opts.MaxSourceHeight > 0 && header.Height() > opts.MaxSourceHeight {
+		return ErrSourceDimensionsTooLarge
+	}
+	if opts.MaxSourcePixels > 0 && int64(header.Width())*int64(header.Height()) > opts.MaxSourcePixels {
+		return ErrSourceTooManyPixels
+	}
+	return nil
+}
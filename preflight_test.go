@@ -0,0 +1,31 @@
+package lilliput
+
+import "testing"
+
+func TestCheckPreflight(t *testing.T) {
+	header := &ImageHeader{width: 1000, height: 500}
+
+	if err := CheckPreflight(header, 100, PreflightOptions{}); err != nil {
+		t.Fatalf("zero-value opts should enforce nothing, got %v", err)
+	}
+
+	if err := CheckPreflight(header, 100, PreflightOptions{MaxSourceBytes: 99}); err != ErrSourceBytesTooLarge {
+		t.Fatalf("got %v, want ErrSourceBytesTooLarge", err)
+	}
+
+	if err := CheckPreflight(header, 100, PreflightOptions{MaxSourceWidth: 999}); err != ErrSourceDimensionsTooLarge {
+		t.Fatalf("got %v, want ErrSourceDimensionsTooLarge (width)", err)
+	}
+
+	if err := CheckPreflight(header, 100, PreflightOptions{MaxSourceHeight: 499}); err != ErrSourceDimensionsTooLarge {
+		t.Fatalf("got %v, want ErrSourceDimensionsTooLarge (height)", err)
+	}
+
+	if err := CheckPreflight(header, 100, PreflightOptions{MaxSourcePixels: 499999}); err != ErrSourceTooManyPixels {
+		t.Fatalf("got %v, want ErrSourceTooManyPixels", err)
+	}
+
+	if err := CheckPreflight(header, 100, PreflightOptions{MaxSourcePixels: 500000}); err != nil {
+		t.Fatalf("exactly at MaxSourcePixels should pass, got %v", err)
+	}
+}
@@ -0,0 +1,105 @@
+package lilliput
+
+import (
+	"image"
+	"image/draw"
+)
+
+// resizeNearest returns a new image.NRGBA of exactly width x height,
+// using nearest-neighbor sampling. This trades resample quality for
+// being simple, fast, and dependency-free.
+func resizeNearest(src image.Image, width, height int) *image.NRGBA {
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	sb := src.Bounds()
+	sw, sh := sb.Dx(), sb.Dy()
+	if sw == 0 || sh == 0 {
+		return dst
+	}
+	for y := 0; y < height; y++ {
+		sy := sb.Min.Y + y*sh/height
+		for x := 0; x < width; x++ {
+			sx := sb.Min.X + x*sw/width
+			dst.Set(x, y, src.At(sx, sy))
+		}
+	}
+	return dst
+}
+
+// resizeToFill scales src so it covers width x height (preserving
+// aspect ratio) and center-crops to exactly width x height. This is the
+// pixel-level implementation behind ImageOpsFit.
+func resizeToFill(src image.Image, width, height int) *image.NRGBA {
+	sb := src.Bounds()
+	sw, sh := sb.Dx(), sb.Dy()
+	if sw == 0 || sh == 0 {
+		return resizeNearest(src, width, height)
+	}
+
+	scale := float64(width) / float64(sw)
+	if s := float64(height) / float64(sh); s > scale {
+		scale = s
+	}
+	scaledW := int(float64(sw)*scale + 0.5)
+	scaledH := int(float64(sh)*scale + 0.5)
+	if scaledW < width {
+		scaledW = width
+	}
+	if scaledH < height {
+		scaledH = height
+	}
+	scaled := resizeNearest(src, scaledW, scaledH)
+
+	x0 := (scaledW - width) / 2
+	y0 := (scaledH - height) / 2
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(dst, dst.Bounds(), scaled, image.Pt(x0, y0), draw.Src)
+	return dst
+}
+
+// rotateImage returns src rotated clockwise by degrees, which must be
+// one of 0, 90, 180, or 270. Any other value returns src unchanged.
+func rotateImage(src image.Image, degrees int) image.Image {
+	switch degrees {
+	case 90:
+		return rotate90(src)
+	case 180:
+		return rotate180(src)
+	case 270:
+		return rotate270(src)
+	default:
+		return src
+	}
+}
+
+func rotate90(src image.Image) *image.NRGBA {
+	b := src.Bounds()
+	dst := image.NewNRGBA(image.Rect(0, 0, b.Dy(), b.Dx()))
+	for y := 0; y < b.Dy(); y++ {
+		for x := 0; x < b.Dx(); x++ {
+			dst.Set(b.Dy()-1-y, x, src.At(b.Min.X+x, b.Min.Y+y))
+		}
+	}
+	return dst
+}
+
+func rotate180(src image.Image) *image.NRGBA {
+	b := src.Bounds()
+	dst := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	for y := 0; y < b.Dy(); y++ {
+		for x := 0; x < b.Dx(); x++ {
+			dst.Set(b.Dx()-1-x, b.Dy()-1-y, src.At(b.Min.X+x, b.Min.Y+y))
+		}
+	}
+	return dst
+}
+
+func rotate270(src image.Image) *image.NRGBA {
+	b := src.Bounds()
+	dst := image.NewNRGBA(image.Rect(0, 0, b.Dy(), b.Dx()))
+	for y := 0; y < b.Dy(); y++ {
+		for x := 0; x < b.Dx(); x++ {
+			dst.Set(y, b.Dx()-1-x, src.At(b.Min.X+x, b.Min.Y+y))
+		}
+	}
+	return dst
+}
@@ -0,0 +1,156 @@
+package lilliput
+
+import (
+	"errors"
+	"io"
+)
+
+// ImageOpsSizeMethod controls how ImageOps.Transform reconciles the
+// source image's aspect ratio with the requested output dimensions.
+type ImageOpsSizeMethod int
+
+const (
+	// ImageOpsNoResize copies the source frame through unchanged.
+	ImageOpsNoResize ImageOpsSizeMethod = iota
+	// ImageOpsFit scales to fill the requested box and center-crops any
+	// overhang, guaranteeing the output is exactly Width x Height.
+	ImageOpsFit
+	// ImageOpsResize stretches the source to exactly Width x Height,
+	// ignoring its original aspect ratio.
+	ImageOpsResize
+	// ImageOpsSkipUpscale behaves like ImageOpsFit, except that when the
+	// source is already smaller than the requested box in both
+	// dimensions, the source is passed through unresized (it is still
+	// re-encoded, so FileType/EncodeOptions/MetadataPolicy still apply).
+	// Use this for "give me a thumbnail at most WxH" requests, where
+	// upscaling would just waste bytes on fabricated detail.
+	ImageOpsSkipUpscale
+)
+
+// Encode option keys. Each encoder only consults the keys it recognizes.
+const (
+	JpegQuality = iota
+	PngCompression
+	WebpQuality
+	GifNumLoops
+)
+
+// ImageOptions configures a single ImageOps.Transform call.
+type ImageOptions struct {
+	// FileType is the output extension, e.g. ".jpeg", ".png", ".webp".
+	FileType string
+
+	Width  int
+	Height int
+
+	ResizeMethod ImageOpsSizeMethod
+
+	// NormalizeOrientation applies the EXIF orientation tag (if any) to
+	// the pixel data so the output requires no client-side rotation.
+	NormalizeOrientation bool
+
+	// MetadataPolicy controls which ancillary metadata (EXIF, XMP, color
+	// profiles) survives into the encoded output. Defaults to
+	// MetadataPreserve.
+	MetadataPolicy MetadataPolicy
+
+	EncodeOptions map[int]int
+}
+
+// ErrFrameBufNoMem is kept for API compatibility. Transform does not
+// return it: the encoders allocate their own output, so the size of the
+// destination buffer passed to Transform does not matter.
+var ErrFrameBufNoMem = errors.New("lilliput: unable to allocate frame buffer")
+
+// ImageOps holds the scratch buffers needed to resize and re-encode a
+// source image. It is not safe for concurrent use, but is cheap to reuse
+// across many Transform calls from the same goroutine.
+type ImageOps struct {
+	frame     *Framebuffer
+	maxPixels int
+}
+
+// NewImageOps returns an ImageOps with a scratch Framebuffer. maxPixels
+// is passed on to NewFramebuffer, which does not use it to size or limit
+// the decoded pixel data.
+func NewImageOps(maxPixels int) *ImageOps {
+	return &ImageOps{
+		frame:     NewFramebuffer(maxPixels),
+		maxPixels: maxPixels,
+	}
+}
+
+// Transform decodes one frame at a time from decoder, resizes it per
+// opts, and encodes the result, returning the encoded bytes. The returned
+// slice is allocated by the encoder; dst is not written to.
+// Animated sources are decoded and re-encoded frame by frame.
+func (o *ImageOps) Transform(decoder Decoder, opts *ImageOptions, dst []byte) ([]byte, error) {
+	header, err := decoder.Header()
+	if err != nil {
+		return nil, err
+	}
+
+	encoder, err := NewEncoder(opts.FileType, decoder, dst, opts.MetadataPolicy)
+	if err != nil {
+		return nil, err
+	}
+	defer encoder.Close()
+
+	dstWidth, dstHeight := opts.Width, opts.Height
+	if dstWidth == 0 {
+		dstWidth = header.Width()
+	}
+	if dstHeight == 0 {
+		dstHeight = header.Height()
+	}
+
+	resizeMethod := opts.ResizeMethod
+	if resizeMethod == ImageOpsSkipUpscale {
+		needRescale := header.Width() > dstWidth || header.Height() > dstHeight
+		if needRescale {
+			resizeMethod = ImageOpsFit
+		} else {
+			resizeMethod = ImageOpsNoResize
+		}
+	}
+
+	for {
+		o.frame.Clear()
+		err = decoder.DecodeTo(o.frame)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if opts.NormalizeOrientation && header.OrientationDegrees() != 0 {
+			// orientation is baked into the pixel data here so encoders
+			// never need to know about EXIF.
+			o.frame.rotate(header.OrientationDegrees())
+		}
+
+		switch resizeMethod {
+		case ImageOpsFit:
+			if err := o.frame.fitInto(dstWidth, dstHeight); err != nil {
+				return nil, err
+			}
+		case ImageOpsResize:
+			if err := o.frame.resizeTo(dstWidth, dstHeight); err != nil {
+				return nil, err
+			}
+		}
+
+		dst, err = encoder.Encode(o.frame, opts.EncodeOptions)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return dst, nil
+}
+
+// Close releases the scratch buffers held by o.
+func (o *ImageOps) Close() {
+	o.frame = nil
+}
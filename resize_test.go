@@ -0,0 +1,67 @@
+package lilliput
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func solidImage(w, h int, c color.Color) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestResizeNearestDimensions(t *testing.T) {
+	src := solidImage(4, 8, color.White)
+	dst := resizeNearest(src, 10, 20)
+	if dst.Bounds().Dx() != 10 || dst.Bounds().Dy() != 20 {
+		t.Fatalf("got %dx%d, want 10x20", dst.Bounds().Dx(), dst.Bounds().Dy())
+	}
+}
+
+func TestResizeToFillDimensions(t *testing.T) {
+	src := solidImage(100, 50, color.White)
+	dst := resizeToFill(src, 20, 20)
+	if dst.Bounds().Dx() != 20 || dst.Bounds().Dy() != 20 {
+		t.Fatalf("got %dx%d, want 20x20", dst.Bounds().Dx(), dst.Bounds().Dy())
+	}
+}
+
+func TestRotateImageSwapsDimensions(t *testing.T) {
+	src := solidImage(10, 20, color.White)
+
+	if got := rotateImage(src, 90); got.Bounds().Dx() != 20 || got.Bounds().Dy() != 10 {
+		t.Fatalf("rotate90: got %dx%d, want 20x10", got.Bounds().Dx(), got.Bounds().Dy())
+	}
+	if got := rotateImage(src, 180); got.Bounds().Dx() != 10 || got.Bounds().Dy() != 20 {
+		t.Fatalf("rotate180: got %dx%d, want 10x20", got.Bounds().Dx(), got.Bounds().Dy())
+	}
+	if got := rotateImage(src, 270); got.Bounds().Dx() != 20 || got.Bounds().Dy() != 10 {
+		t.Fatalf("rotate270: got %dx%d, want 20x10", got.Bounds().Dx(), got.Bounds().Dy())
+	}
+	if got := rotateImage(src, 0); got != image.Image(src) {
+		t.Fatalf("rotate0 should return src unchanged")
+	}
+}
+
+func TestRotate90PixelPlacement(t *testing.T) {
+	// a 2x1 image where (0,0) is red and (1,0) is blue; rotating 90
+	// clockwise should put red at the top-right corner.
+	src := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+	src.Set(0, 0, color.NRGBA{R: 255, A: 255})
+	src.Set(1, 0, color.NRGBA{B: 255, A: 255})
+
+	dst := rotate90(src)
+	if dst.Bounds().Dx() != 1 || dst.Bounds().Dy() != 2 {
+		t.Fatalf("got %dx%d, want 1x2", dst.Bounds().Dx(), dst.Bounds().Dy())
+	}
+	r, _, _, _ := dst.At(0, 0).RGBA()
+	if r == 0 {
+		t.Fatalf("expected red pixel at (0,0) after 90deg rotation")
+	}
+}
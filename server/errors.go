@@ -0,0 +1,16 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	errMissingURL   = errors.New("server: missing required \"url\" parameter")
+	errBadDimension = errors.New("server: \"w\" and \"h\" must be integers")
+	errBadFormat    = errors.New("server: \"format\" must be one of jpeg, png, webp, gif, avif")
+)
+
+func errUpstreamStatus(code int) error {
+	return fmt.Errorf("server: upstream returned status %d", code)
+}
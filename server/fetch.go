@@ -0,0 +1,51 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net/http"
+)
+
+type upstreamResponse struct {
+	body         io.ReadCloser
+	contentType  string
+	etag         string
+	lastModified string
+	notModified  bool
+}
+
+// fetchUpstream retrieves url, forwarding the client's validators so an
+// upstream 304 short-circuits the transform entirely.
+func (s *Server) fetchUpstream(ctx context.Context, url, ifModifiedSince, ifNoneMatch string) (*upstreamResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if ifModifiedSince != "" {
+		req.Header.Set("If-Modified-Since", ifModifiedSince)
+	}
+	if ifNoneMatch != "" {
+		req.Header.Set("If-None-Match", ifNoneMatch)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified {
+		resp.Body.Close()
+		return &upstreamResponse{body: http.NoBody, notModified: true}, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, errUpstreamStatus(resp.StatusCode)
+	}
+
+	return &upstreamResponse{
+		body:         resp.Body,
+		contentType:  resp.Header.Get("Content-Type"),
+		etag:         resp.Header.Get("ETag"),
+		lastModified: resp.Header.Get("Last-Modified"),
+	}, nil
+}
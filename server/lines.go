@@ -0,0 +1,30 @@
+package server
+
+import (
+	"bufio"
+	"io"
+)
+
+// lineReader reads a single newline-terminated header line and then
+// exposes the remainder of the underlying reader through the same
+// bufio.Reader, so binary image data that bufio buffered past the header
+// line is returned by Read rather than lost.
+type lineReader struct {
+	r *bufio.Reader
+}
+
+func newLineReader(r io.Reader) *lineReader {
+	return &lineReader{r: bufio.NewReader(r)}
+}
+
+func (l *lineReader) ReadLine() ([]byte, error) {
+	line, err := l.r.ReadBytes('\n')
+	if err != nil && len(line) == 0 {
+		return nil, err
+	}
+	return line, nil
+}
+
+func (l *lineReader) Read(p []byte) (int, error) {
+	return l.r.Read(p)
+}
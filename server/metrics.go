@@ -0,0 +1,52 @@
+package server
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// statusTotal counts requests by terminal outcome: "success",
+// "served-original" (worker failed/timed out, original bytes returned),
+// "client-cache" (conditional request answered with 304), and
+// "request-failed" (the request could not be served at all).
+var statusTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "lilliput",
+		Subsystem: "server",
+		Name:      "requests_total",
+		Help:      "Total /resize requests by outcome.",
+	},
+	[]string{"status"},
+)
+
+var transformDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Namespace: "lilliput",
+		Subsystem: "server",
+		Name:      "transform_duration_seconds",
+		Help:      "Time spent producing the resized image (cache lookup or resize worker subprocess), by outcome.",
+		Buckets:   prometheus.DefBuckets,
+	},
+	[]string{"status"},
+)
+
+func init() {
+	prometheus.MustRegister(statusTotal, transformDuration)
+}
+
+func observeStatus(status string) {
+	statusTotal.WithLabelValues(status).Inc()
+}
+
+type transformTimer struct {
+	start time.Time
+}
+
+func startTransformTimer() transformTimer {
+	return transformTimer{start: time.Now()}
+}
+
+func (t transformTimer) observeDuration(status string) {
+	transformDuration.WithLabelValues(status).Observe(time.Since(t.start).Seconds())
+}
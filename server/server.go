@@ -0,0 +1,258 @@
+// Package server exposes lilliput's resize/transcode pipeline as an HTTP
+// service, modeled after GitLab Workhorse's image resizer: every
+// transform runs in a short-lived subprocess so a crash while decoding
+// or encoding can't take down the parent, and slow or disconnected
+// clients are bounded by a context deadline rather than by however long
+// decoding happens to take.
+package server
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/discordapp/lilliput"
+	"github.com/discordapp/lilliput/cache"
+)
+
+// Config controls the behavior of a Server.
+type Config struct {
+	// MaxConcurrency bounds the number of transforms running at once,
+	// independent of how many HTTP requests are in flight. Defaults to 8.
+	MaxConcurrency int
+
+	// RequestTimeout bounds how long a single /resize request may take
+	// end to end, covering both the upstream fetch and the worker
+	// subprocess. Defaults to 10s.
+	RequestTimeout time.Duration
+
+	// WorkerPath is the binary re-exec'd in --resize-worker mode for
+	// each transform. Defaults to os.Args[0].
+	WorkerPath string
+
+	// HTTPClient fetches the upstream "url" parameter. Defaults to
+	// http.DefaultClient.
+	HTTPClient *http.Client
+
+	// MaxUpstreamBytes caps how much of the upstream response body is
+	// read before the request is failed. Defaults to 32MiB.
+	MaxUpstreamBytes int64
+
+	// Cache, if set, is consulted before spawning a resize worker and
+	// populated after a successful transform, keyed on the upstream
+	// bytes and the requested transform. Concurrent requests that share
+	// a cache key are coalesced so only one of them actually runs the
+	// worker subprocess. Nil disables caching.
+	Cache cache.TransformCache
+
+	// CacheTTL controls how long entries in Cache stay valid. Defaults
+	// to cache.DefaultTTL.
+	CacheTTL time.Duration
+
+	// Preflight bounds the decoded dimensions/pixel count of upstream
+	// images before the worker subprocess resizes them, protecting
+	// against decompression-bomb sources (e.g. a tiny file that decodes
+	// to a 60000x60000 bitmap). A zero value enforces no limit.
+	Preflight lilliput.PreflightOptions
+}
+
+const defaultMaxUpstreamBytes = 32 * 1024 * 1024
+
+// Server serves GET /resize requests.
+type Server struct {
+	cfg    Config
+	sem    chan struct{}
+	client *http.Client
+	group  cache.Group
+}
+
+// New returns a Server configured per cfg, filling in defaults for any
+// zero-valued fields.
+func New(cfg Config) *Server {
+	if cfg.MaxConcurrency <= 0 {
+		cfg.MaxConcurrency = 8
+	}
+	if cfg.RequestTimeout <= 0 {
+		cfg.RequestTimeout = 10 * time.Second
+	}
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = http.DefaultClient
+	}
+	if cfg.MaxUpstreamBytes <= 0 {
+		cfg.MaxUpstreamBytes = defaultMaxUpstreamBytes
+	}
+	if cfg.CacheTTL <= 0 {
+		cfg.CacheTTL = cache.DefaultTTL
+	}
+	return &Server{
+		cfg:    cfg,
+		sem:    make(chan struct{}, cfg.MaxConcurrency),
+		client: cfg.HTTPClient,
+	}
+}
+
+type resizeRequest struct {
+	url    string
+	width  int
+	height int
+	fit    string
+	format string
+}
+
+func parseResizeRequest(r *http.Request) (resizeRequest, error) {
+	q := r.URL.Query()
+	req := resizeRequest{
+		url:    q.Get("url"),
+		fit:    q.Get("fit"),
+		format: q.Get("format"),
+	}
+	if req.url == "" {
+		return req, errMissingURL
+	}
+	var err error
+	if w := q.Get("w"); w != "" {
+		if req.width, err = strconv.Atoi(w); err != nil {
+			return req, errBadDimension
+		}
+	}
+	if h := q.Get("h"); h != "" {
+		if req.height, err = strconv.Atoi(h); err != nil {
+			return req, errBadDimension
+		}
+	}
+	if !allowedFormats[req.format] {
+		return req, errBadFormat
+	}
+	return req, nil
+}
+
+// allowedFormats mirrors the extensions lilliput.NewEncoder recognizes,
+// plus "" for "keep the source's format". webp and avif are recognized
+// but fail with ErrFormatNotSupported in this build, so those requests
+// fall back to serving the original bytes. format is user-supplied and
+// ends up both in the worker's encode call and (via imageOptions) in the
+// cache key, so it's rejected here rather than left to fail later
+// downstream.
+var allowedFormats = map[string]bool{
+	"":     true,
+	"jpeg": true,
+	"png":  true,
+	"webp": true,
+	"gif":  true,
+	"avif": true,
+}
+
+func (req resizeRequest) resizeMethod() lilliput.ImageOpsSizeMethod {
+	if req.fit == "stretch" {
+		return lilliput.ImageOpsResize
+	}
+	return lilliput.ImageOpsFit
+}
+
+// imageOptions returns the ImageOptions the worker subprocess will
+// transform with for req, for use as a cache.Key input. RunWorker always
+// passes NormalizeOrientation: true, so that's mirrored here too.
+func (req resizeRequest) imageOptions() *lilliput.ImageOptions {
+	return &lilliput.ImageOptions{
+		FileType:             req.format,
+		Width:                req.width,
+		Height:               req.height,
+		ResizeMethod:         req.resizeMethod(),
+		NormalizeOrientation: true,
+	}
+}
+
+// ServeHTTP implements GET /resize?url=&w=&h=&fit=&format=.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	req, err := parseResizeRequest(r)
+	if err != nil {
+		observeStatus("request-failed")
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), s.cfg.RequestTimeout)
+	defer cancel()
+
+	select {
+	case s.sem <- struct{}{}:
+		defer func() { <-s.sem }()
+	case <-ctx.Done():
+		observeStatus("request-failed")
+		http.Error(w, "server busy", http.StatusServiceUnavailable)
+		return
+	}
+
+	upstream, err := s.fetchUpstream(ctx, req.url, r.Header.Get("If-Modified-Since"), r.Header.Get("If-None-Match"))
+	if err != nil {
+		observeStatus("request-failed")
+		http.Error(w, "failed to fetch upstream image", http.StatusBadGateway)
+		return
+	}
+	defer upstream.body.Close()
+
+	if upstream.notModified {
+		observeStatus("client-cache")
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	src, err := ioutil.ReadAll(io.LimitReader(upstream.body, s.cfg.MaxUpstreamBytes+1))
+	if err != nil {
+		observeStatus("request-failed")
+		http.Error(w, "failed to read upstream image", http.StatusBadGateway)
+		return
+	}
+	if int64(len(src)) > s.cfg.MaxUpstreamBytes {
+		observeStatus("request-failed")
+		http.Error(w, "upstream image too large", http.StatusBadGateway)
+		return
+	}
+
+	if upstream.etag != "" {
+		w.Header().Set("ETag", upstream.etag)
+	}
+	if upstream.lastModified != "" {
+		w.Header().Set("Last-Modified", upstream.lastModified)
+	}
+
+	timer := startTransformTimer()
+	out, err := s.transformCached(ctx, src, req)
+	if err != nil {
+		// the worker crashed, timed out, or produced no output; fall
+		// back to streaming the original bytes through rather than
+		// failing the request outright.
+		timer.observeDuration("served-original")
+		observeStatus("served-original")
+		w.Header().Set("Content-Type", upstream.contentType)
+		w.Write(src)
+		return
+	}
+	timer.observeDuration("success")
+	observeStatus("success")
+
+	w.Header().Set("Content-Type", contentTypeForFormat(req.format, upstream.contentType))
+	w.Write(out)
+}
+
+func contentTypeForFormat(format, fallback string) string {
+	switch format {
+	case "jpeg", "jpg":
+		return "image/jpeg"
+	case "png":
+		return "image/png"
+	case "webp":
+		return "image/webp"
+	case "gif":
+		return "image/gif"
+	}
+	return fallback
+}
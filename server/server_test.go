@@ -0,0 +1,22 @@
+package server
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseResizeRequestRejectsUnknownFormat(t *testing.T) {
+	r := httptest.NewRequest("GET", "/resize?url=http://example.com/a.jpg&format=../../../etc/passwd", nil)
+	if _, err := parseResizeRequest(r); err != errBadFormat {
+		t.Fatalf("got %v, want errBadFormat", err)
+	}
+}
+
+func TestParseResizeRequestAllowsKnownFormats(t *testing.T) {
+	for _, format := range []string{"", "jpeg", "png", "webp", "gif", "avif"} {
+		r := httptest.NewRequest("GET", "/resize?url=http://example.com/a.jpg&format="+format, nil)
+		if _, err := parseResizeRequest(r); err != nil {
+			t.Errorf("format %q: got %v, want nil", format, err)
+		}
+	}
+}
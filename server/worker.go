@@ -0,0 +1,170 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/discordapp/lilliput"
+	"github.com/discordapp/lilliput/cache"
+)
+
+// WorkerFlag is the hidden flag a binary embedding Server should check
+// for on startup, dispatching to RunWorker when present instead of
+// running its normal main. This keeps the subprocess a re-exec of the
+// same binary rather than a separate build artifact.
+const WorkerFlag = "--resize-worker"
+
+// workerRequest is sent to the subprocess on stdin as JSON, followed by
+// the raw source image bytes.
+type workerRequest struct {
+	Width        int                      `json:"width"`
+	Height       int                      `json:"height"`
+	Format       string                   `json:"format"`
+	ResizeMethod int                      `json:"resize_method"`
+	Preflight    lilliput.PreflightOptions `json:"preflight"`
+}
+
+var errWorkerFailed = errors.New("server: resize worker exited without producing output")
+
+// transformCached checks s.cfg.Cache for src/req before falling back to
+// s.transform, coalescing concurrent requests for the same key so only
+// one of them spawns a worker subprocess. It is a no-op wrapper around
+// s.transform when no Cache is configured.
+func (s *Server) transformCached(ctx context.Context, src []byte, req resizeRequest) ([]byte, error) {
+	if s.cfg.Cache == nil {
+		return s.transform(ctx, src, req)
+	}
+
+	key := cache.Key(src, req.imageOptions())
+	if cached, ok := s.cfg.Cache.Get(key); ok {
+		return cached, nil
+	}
+
+	return s.group.Do(key, func() ([]byte, error) {
+		if cached, ok := s.cfg.Cache.Get(key); ok {
+			return cached, nil
+		}
+		out, err := s.transform(ctx, src, req)
+		if err != nil {
+			return nil, err
+		}
+		s.cfg.Cache.Put(key, out, s.cfg.CacheTTL)
+		return out, nil
+	})
+}
+
+// transform resizes src per req by re-executing the current binary in
+// --resize-worker mode and piping src to it over stdin. Running the
+// actual decode/encode out of process means a crash there takes down the
+// worker, not the server.
+func (s *Server) transform(ctx context.Context, src []byte, req resizeRequest) ([]byte, error) {
+	workerPath := s.cfg.WorkerPath
+	if workerPath == "" {
+		workerPath = os.Args[0]
+	}
+
+	cmd := exec.CommandContext(ctx, workerPath, WorkerFlag)
+
+	header, err := json.Marshal(workerRequest{
+		Width:        req.width,
+		Height:       req.height,
+		Format:       req.format,
+		ResizeMethod: int(req.resizeMethod()),
+		Preflight:    s.cfg.Preflight,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var stdin bytes.Buffer
+	stdin.Write(header)
+	stdin.WriteByte('\n')
+	stdin.Write(src)
+	cmd.Stdin = &stdin
+
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = ioutil.Discard
+
+	if err := cmd.Run(); err != nil {
+		return nil, err
+	}
+	if stdout.Len() == 0 {
+		return nil, errWorkerFailed
+	}
+	return stdout.Bytes(), nil
+}
+
+// RunWorker implements the --resize-worker entry point: it reads a
+// workerRequest header line followed by the raw image from stdin,
+// transforms it, and writes the encoded result to stdout. It is intended
+// to be called from main() before flag parsing proceeds any further,
+// e.g.:
+//
+//	if len(os.Args) > 1 && os.Args[1] == server.WorkerFlag {
+//		os.Exit(server.RunWorker(os.Stdin, os.Stdout))
+//	}
+func RunWorker(in io.Reader, out io.Writer) int {
+	r := newLineReader(in)
+	headerLine, err := r.ReadLine()
+	if err != nil {
+		return 1
+	}
+
+	var req workerRequest
+	if err := json.Unmarshal(headerLine, &req); err != nil {
+		return 1
+	}
+
+	src, err := ioutil.ReadAll(r)
+	if err != nil {
+		return 1
+	}
+
+	decoder, err := lilliput.NewDecoder(src)
+	if err != nil {
+		return 1
+	}
+	defer decoder.Close()
+
+	header, err := decoder.Header()
+	if err != nil {
+		return 1
+	}
+	if err := lilliput.CheckPreflight(header, len(src), req.Preflight); err != nil {
+		return 1
+	}
+
+	ops := lilliput.NewImageOps(8192)
+	defer ops.Close()
+
+	// an empty format keeps the source's own format
+	format := req.Format
+	if format == "" {
+		format = "." + strings.ToLower(decoder.Description())
+	}
+
+	dst := make([]byte, 50*1024*1024)
+	dst, err = ops.Transform(decoder, &lilliput.ImageOptions{
+		FileType:             format,
+		Width:                req.Width,
+		Height:               req.Height,
+		ResizeMethod:         lilliput.ImageOpsSizeMethod(req.ResizeMethod),
+		NormalizeOrientation: true,
+	}, dst)
+	if err != nil {
+		return 1
+	}
+
+	if _, err := out.Write(dst); err != nil {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,46 @@
+package lilliput
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+)
+
+// ErrSourceTooLarge is returned by NewStreamDecoder once more than
+// StreamDecoderOptions.MaxBytes has been read from the underlying
+// io.Reader.
+var ErrSourceTooLarge = errors.New("lilliput: source exceeds MaxBytes")
+
+// StreamDecoderOptions configures NewStreamDecoder.
+type StreamDecoderOptions struct {
+	// MaxBytes caps the total number of bytes read from the source
+	// reader. Zero means unlimited. Once exceeded, NewStreamDecoder
+	// returns ErrSourceTooLarge and no further bytes are read from the
+	// source.
+	MaxBytes int64
+}
+
+// NewStreamDecoder returns a Decoder for the bytes read from r, capped at
+// opts.MaxBytes. Unlike NewDecoder, the caller doesn't have to read r
+// into a []byte first: NewStreamDecoder does that bounded read itself
+// and returns ErrSourceTooLarge instead of materializing an oversized
+// payload. Today's format decoders (jpeg.go/png.go/gif.go/webp.go/avif.go)
+// all operate on a fully materialized []byte under the hood, so the whole
+// (size-capped) source is still read before decoding begins; there is no
+// on-demand / streaming decode yet.
+func NewStreamDecoder(r io.Reader, opts StreamDecoderOptions) (Decoder, error) {
+	src := r
+	if opts.MaxBytes > 0 {
+		src = io.LimitReader(r, opts.MaxBytes+1)
+	}
+
+	buf, err := ioutil.ReadAll(src)
+	if err != nil {
+		return nil, err
+	}
+	if opts.MaxBytes > 0 && int64(len(buf)) > opts.MaxBytes {
+		return nil, ErrSourceTooLarge
+	}
+
+	return NewDecoder(buf)
+}
@@ -0,0 +1,32 @@
+package lilliput
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestNewStreamDecoderMaxBytes(t *testing.T) {
+	buf := encodeTestJPEG(t)
+
+	if _, err := NewStreamDecoder(bytes.NewReader(buf), StreamDecoderOptions{MaxBytes: int64(len(buf))}); err != nil {
+		t.Fatalf("expected success at exactly MaxBytes, got %v", err)
+	}
+
+	_, err := NewStreamDecoder(bytes.NewReader(buf), StreamDecoderOptions{MaxBytes: int64(len(buf)) - 1})
+	if err != ErrSourceTooLarge {
+		t.Fatalf("got %v, want ErrSourceTooLarge", err)
+	}
+}
+
+func TestNewStreamDecoderUnbounded(t *testing.T) {
+	buf := encodeTestJPEG(t)
+	dec, err := NewStreamDecoder(bytes.NewReader(buf), StreamDecoderOptions{})
+	if err != nil {
+		t.Fatalf("NewStreamDecoder: %v", err)
+	}
+	defer dec.Close()
+
+	if _, err := dec.Header(); err != nil {
+		t.Fatalf("Header: %v", err)
+	}
+}
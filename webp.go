@@ -0,0 +1,17 @@
+package lilliput
+
+func isWebp(buf []byte) bool {
+	return len(buf) >= 12 && string(buf[0:4]) == "RIFF" && string(buf[8:12]) == "WEBP"
+}
+
+// newWebpDecoder always fails: this build has no WEBP codec. isWebp is
+// still used by NewDecoder so WEBP sources are identified and rejected
+// with ErrFormatNotSupported rather than the less specific ErrInvalidImage.
+func newWebpDecoder(buf []byte) (Decoder, error) {
+	return nil, ErrFormatNotSupported
+}
+
+// newWebpEncoder always fails; see newWebpDecoder.
+func newWebpEncoder(decodedBy Decoder, dst []byte, policy MetadataPolicy) (Encoder, error) {
+	return nil, ErrFormatNotSupported
+}
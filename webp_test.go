@@ -0,0 +1,21 @@
+package lilliput
+
+import "testing"
+
+func TestIsWebp(t *testing.T) {
+	buf := append([]byte("RIFF\x00\x00\x00\x00WEBP"), 0)
+	if !isWebp(buf) {
+		t.Fatalf("expected RIFF....WEBP to sniff as webp")
+	}
+	if isWebp([]byte("not a webp")) {
+		t.Fatalf("expected non-webp bytes to not sniff as webp")
+	}
+}
+
+func TestNewDecoderWebpUnsupported(t *testing.T) {
+	buf := append([]byte("RIFF\x00\x00\x00\x00WEBP"), 0)
+	_, err := NewDecoder(buf)
+	if err != ErrFormatNotSupported {
+		t.Fatalf("got %v, want ErrFormatNotSupported", err)
+	}
+}